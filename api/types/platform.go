@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Canonical returns the stable string form of the platform:
+//
+//	os[/architecture[/variant]][;osversion=<version>][;feature=<a,b,...>]
+//
+// Unlike the generated String() method, which dumps the proto debug
+// form, Canonical produces a string that round-trips through
+// ParsePlatform and matches the form emitted by containerd/platforms,
+// except that a Variant is omitted when Architecture is empty.
+func (p *Platform) Canonical() string {
+	if p == nil || p.OS == "" {
+		return "unknown"
+	}
+
+	var parts []string
+	parts = append(parts, p.OS)
+	if p.Architecture != "" {
+		parts = append(parts, p.Architecture)
+		if p.Variant != "" {
+			parts = append(parts, p.Variant)
+		}
+	}
+	s := strings.Join(parts, "/")
+
+	if p.OSVersion != "" {
+		s += ";osversion=" + p.OSVersion
+	}
+
+	if len(p.OSFeatures) > 0 {
+		features := append([]string(nil), p.OSFeatures...)
+		sort.Strings(features)
+		s += ";feature=" + strings.Join(features, ",")
+	}
+
+	return s
+}
+
+// ParsePlatform parses a platform specifier produced by Canonical, or the
+// short `os/architecture[/variant]` form commonly used for CLI flags,
+// labels and content store filters, into a Platform.
+func ParsePlatform(s string) (*Platform, error) {
+	if s == "" {
+		return nil, fmt.Errorf("platform specifier cannot be empty")
+	}
+
+	fields := strings.Split(s, ";")
+
+	parts := strings.Split(fields[0], "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid platform specifier %q: missing os", s)
+	}
+
+	p := &Platform{OS: parts[0]}
+	switch len(parts) {
+	case 1:
+	case 2:
+		p.Architecture = parts[1]
+	case 3:
+		p.Architecture = parts[1]
+		p.Variant = parts[2]
+	default:
+		return nil, fmt.Errorf("invalid platform specifier %q: too many slashes", s)
+	}
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid platform specifier %q: malformed attribute %q", s, field)
+		}
+
+		switch key {
+		case "osversion":
+			if value == "" {
+				return nil, fmt.Errorf("invalid platform specifier %q: empty osversion", s)
+			}
+			p.OSVersion = value
+		case "feature":
+			if value == "" {
+				return nil, fmt.Errorf("invalid platform specifier %q: empty feature list", s)
+			}
+			p.OSFeatures = strings.Split(value, ",")
+		default:
+			return nil, fmt.Errorf("invalid platform specifier %q: unknown attribute %q", s, key)
+		}
+	}
+
+	return p, nil
+}
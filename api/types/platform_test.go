@@ -0,0 +1,166 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlatformCanonical(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		platform *Platform
+		expected string
+	}{
+		{
+			name:     "OSArchVariant",
+			platform: &Platform{OS: "linux", Architecture: "arm", Variant: "v8"},
+			expected: "linux/arm/v8",
+		},
+		{
+			name:     "OSOnly",
+			platform: &Platform{OS: "linux"},
+			expected: "linux",
+		},
+		{
+			name:     "WindowsVersion",
+			platform: &Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+			expected: "windows/amd64;osversion=10.0.17763.1234",
+		},
+		{
+			name:     "FeaturesSorted",
+			platform: &Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon", "sse4"}},
+			expected: "linux/arm/v7;feature=neon,sse4",
+		},
+		{
+			name:     "FeaturesUnsortedInput",
+			platform: &Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"sse4", "neon"}},
+			expected: "linux/arm/v7;feature=neon,sse4",
+		},
+		{
+			name:     "Nil",
+			platform: nil,
+			expected: "unknown",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.platform.Canonical(); got != tc.expected {
+				t.Fatalf("Canonical() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    string
+		expected *Platform
+		err      bool
+	}{
+		{
+			name:     "OSOnly",
+			input:    "linux",
+			expected: &Platform{OS: "linux"},
+		},
+		{
+			name:     "OSArch",
+			input:    "linux/amd64",
+			expected: &Platform{OS: "linux", Architecture: "amd64"},
+		},
+		{
+			name:     "OSArchVariant",
+			input:    "linux/arm/v8",
+			expected: &Platform{OS: "linux", Architecture: "arm", Variant: "v8"},
+		},
+		{
+			name:     "ArmVariants",
+			input:    "linux/arm/v5",
+			expected: &Platform{OS: "linux", Architecture: "arm", Variant: "v5"},
+		},
+		{
+			name:     "WindowsVersion",
+			input:    "windows/amd64;osversion=10.0.17763.1234",
+			expected: &Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+		},
+		{
+			name:     "Features",
+			input:    "linux/arm/v7;feature=neon,sse4",
+			expected: &Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon", "sse4"}},
+		},
+		{
+			name:  "Empty",
+			input: "",
+			err:   true,
+		},
+		{
+			name:  "TooManySlashes",
+			input: "linux/amd64/v8/extra",
+			err:   true,
+		},
+		{
+			name:  "MalformedAttribute",
+			input: "linux/amd64;osversion",
+			err:   true,
+		},
+		{
+			name:  "UnknownAttribute",
+			input: "linux/amd64;bogus=1",
+			err:   true,
+		},
+		{
+			name:  "EmptyOSVersion",
+			input: "windows/amd64;osversion=",
+			err:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePlatform(tc.input)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("ParsePlatform(%q) = %v, want error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePlatform(%q) returned unexpected error: %v", tc.input, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("ParsePlatform(%q) = %#v, want %#v", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPlatformCanonicalRoundTrip(t *testing.T) {
+	for _, p := range []*Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm", Variant: "v7"},
+		{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+		{OS: "linux", Architecture: "arm64", OSFeatures: []string{"sse4"}},
+	} {
+		canonical := p.Canonical()
+		parsed, err := ParsePlatform(canonical)
+		if err != nil {
+			t.Fatalf("ParsePlatform(%q) returned unexpected error: %v", canonical, err)
+		}
+		if !reflect.DeepEqual(parsed, p) {
+			t.Fatalf("round trip of %#v through %q produced %#v", p, canonical, parsed)
+		}
+	}
+}
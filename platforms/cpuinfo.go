@@ -0,0 +1,77 @@
+//go:build !windows
+// +build !windows
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package platforms
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// cpuVariant returns the detected CPU variant for the current runtime
+// architecture. It currently only reports a meaningful value for ARM,
+// where the exact revision governs which instructions are available.
+func cpuVariant() string {
+	// arm64 carries no variant: the v8 instruction set is a fixed
+	// baseline, unlike 32-bit arm where the revision varies by device.
+	if runtime.GOARCH != "arm" {
+		return ""
+	}
+
+	return getCPUVariantFromCPUInfo()
+}
+
+// getCPUVariantFromCPUInfo parses /proc/cpuinfo looking for the "CPU
+// architecture" field that the Linux ARM kernel exposes, and maps it to
+// the OCI variant string.
+func getCPUVariantFromCPUInfo() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		if key != "CPU architecture" {
+			continue
+		}
+
+		switch strings.TrimSpace(parts[1]) {
+		case "5":
+			return "v5"
+		case "6":
+			return "v6"
+		case "7":
+			return "v7"
+		case "8":
+			return "v8"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,450 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package platforms provides a toolkit for normalizing, matching and
+// specifying container platforms.
+//
+// Centrally, a platform is comprised of an operating system, an
+// architecture and an optional variant, following the OCI image-spec
+// conventions. In addition, an operating system version and a set of
+// required operating system features may be specified; these are most
+// relevant on Windows, where the exact build number selects the base
+// layer used to run a container, and on ARM, where a variant such as
+// `v7` implies a CPU feature set.
+//
+// An empty OS on the requested platform is normalized to the host's OS by
+// Normalize, so it is matched as "the host", not as "any". An empty
+// Architecture is left empty by Normalize and matches only candidates
+// whose Architecture is also empty. Variant, OSVersion and OSFeatures, on
+// the other hand, behave permissively when left empty on the requested
+// platform: an empty Variant or OSVersion matches any candidate value, and
+// an empty OSFeatures set is trivially satisfied. The exception is the
+// arm architecture, where Normalize fills an empty Variant with `v7`, so
+// that an empty arm Variant matches `v7`, `v6` and `v5` but not `v8`.
+// A non-empty OSFeatures
+// set on the requested platform must be a subset of the candidate's
+// OSFeatures.
+package platforms
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	specifierRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// Matcher matches platforms specifications, provided by an image or
+// runtime.
+type Matcher interface {
+	Match(platform specs.Platform) bool
+}
+
+// MatchComparer is a Matcher that can additionally rank two platforms it
+// both match, so that the best candidate can be selected rather than an
+// arbitrary one accepted through fallback rules such as ARM-variant
+// compatibility.
+type MatchComparer interface {
+	Matcher
+
+	Less(specs.Platform, specs.Platform) bool
+}
+
+// NewMatcher returns a matcher that matches any platform with the same
+// OS and architecture as platform, following the OCI subset rules for
+// OSVersion and OSFeatures, and the CPU-variant fallback rules for ARM
+// (for example, a request for `v7` also accepts `v6` and `v5`, since a
+// higher ARM revision's instruction set is a superset of the lower
+// ones). Use Less to prefer an exact match over one accepted only
+// through fallback.
+func NewMatcher(platform specs.Platform) MatchComparer {
+	return newDefaultMatcher(platform)
+}
+
+type matcher struct {
+	specs.Platform
+}
+
+func newDefaultMatcher(platform specs.Platform) MatchComparer {
+	return &matcher{
+		Platform: Normalize(platform),
+	}
+}
+
+func (m *matcher) Match(other specs.Platform) bool {
+	normalized := Normalize(other)
+
+	if m.OS != normalized.OS || m.Architecture != normalized.Architecture {
+		return false
+	}
+
+	if !variantMatches(m.Variant, normalized.Variant) {
+		return false
+	}
+
+	if m.OSVersion != "" && m.OSVersion != normalized.OSVersion {
+		return false
+	}
+
+	return hasAllFeatures(m.OSFeatures, normalized.OSFeatures)
+}
+
+// Less ranks p1 ahead of p2 when p1 matches and p2 does not, or when
+// both match but p1's variant is a closer (or exact) fit for the
+// requested variant than p2's.
+func (m *matcher) Less(p1, p2 specs.Platform) bool {
+	m1, m2 := m.Match(p1), m.Match(p2)
+	if m1 != m2 {
+		return m1
+	}
+	if !m1 {
+		return false
+	}
+
+	return variantFallbackDistance(m.Variant, Normalize(p1).Variant) < variantFallbackDistance(m.Variant, Normalize(p2).Variant)
+}
+
+func (m *matcher) String() string {
+	return Format(m.Platform)
+}
+
+// variantMatches reports whether a candidate variant satisfies a
+// requested variant. An empty requested variant matches anything; ARM
+// variants additionally accept any candidate at or below the requested
+// revision (v7 accepts v6 and v5) since a higher ARM revision is a
+// superset of the instructions offered by the lower ones.
+func variantMatches(requested, candidate string) bool {
+	if requested == "" {
+		return true
+	}
+	if requested == candidate {
+		return true
+	}
+
+	requestedRev, rok := armRevision(requested)
+	candidateRev, cok := armRevision(candidate)
+	if rok && cok {
+		return candidateRev <= requestedRev
+	}
+
+	return false
+}
+
+// variantFallbackDistance ranks how far a candidate variant is from the
+// exact requested variant, for use by Less: 0 is an exact match, larger
+// is further down the ARM-revision fallback chain.
+func variantFallbackDistance(requested, candidate string) int {
+	if requested == candidate {
+		return 0
+	}
+
+	requestedRev, rok := armRevision(requested)
+	candidateRev, cok := armRevision(candidate)
+	if rok && cok {
+		return requestedRev - candidateRev
+	}
+
+	return 0
+}
+
+func armRevision(variant string) (int, bool) {
+	if len(variant) < 2 || variant[0] != 'v' {
+		return 0, false
+	}
+	rev, err := strconv.Atoi(variant[1:])
+	if err != nil {
+		return 0, false
+	}
+	return rev, true
+}
+
+// hasAllFeatures reports whether every feature requested is present in
+// the candidate's feature set.
+func hasAllFeatures(requested, candidate []string) bool {
+	if len(requested) == 0 {
+		return true
+	}
+
+	have := make(map[string]struct{}, len(candidate))
+	for _, f := range candidate {
+		have[f] = struct{}{}
+	}
+
+	for _, f := range requested {
+		if _, ok := have[f]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Default returns the default matcher for the current platform.
+func Default() MatchComparer {
+	return Only(DefaultSpec())
+}
+
+// Only returns a matcher that matches against platform, using the same
+// rules as NewMatcher.
+func Only(platform specs.Platform) MatchComparer {
+	return NewMatcher(platform)
+}
+
+// DefaultString returns the default string specifier for the platform.
+func DefaultString() string {
+	return Format(DefaultSpec())
+}
+
+// DefaultSpec returns the current platform's default spec.
+func DefaultSpec() specs.Platform {
+	return specs.Platform{
+		OS:           runtime.GOOS,
+		Architecture: runtime.GOARCH,
+		// The Variant field will be empty if arch != ARM.
+		Variant: cpuVariant(),
+	}
+}
+
+// Format returns a string specifier from the provided platform
+// specification, following the convention
+// `<os>/<architecture>[/<variant>][;osversion=<version>][;feature=<a,b,...>]`.
+// OSVersion and OSFeatures, when present, are appended as `;`-separated
+// attributes so that the result round-trips through Parse.
+func Format(platform specs.Platform) string {
+	if platform.OS == "" {
+		return "unknown"
+	}
+
+	s := joinNotEmpty(platform.OS, platform.Architecture, platform.Variant)
+
+	if platform.OSVersion != "" {
+		s += ";osversion=" + platform.OSVersion
+	}
+
+	if len(platform.OSFeatures) > 0 {
+		features := append([]string(nil), platform.OSFeatures...)
+		sort.Strings(features)
+		s += ";feature=" + strings.Join(features, ",")
+	}
+
+	return s
+}
+
+func joinNotEmpty(s ...string) string {
+	var ss []string
+	for _, s := range s {
+		if s == "" {
+			continue
+		}
+
+		ss = append(ss, s)
+	}
+
+	return strings.Join(ss, "/")
+}
+
+// Parse parses the platform specifier from the string into a specs.Platform.
+//
+// Platform specifiers are in the format
+// `<os>|<arch>|<os>/<arch>[/<variant>][;osversion=<version>][;feature=<a,b,...>]`.
+// The OS and architecture must be recognized and will be normalized; the
+// `osversion`/`feature` attributes, when present, are parsed verbatim so
+// that Format's output round-trips through Parse.
+func Parse(specifier string) (specs.Platform, error) {
+	if strings.Contains(specifier, "*") {
+		return specs.Platform{}, fmt.Errorf("%q: wildcards not yet supported", specifier)
+	}
+
+	fields := strings.Split(specifier, ";")
+
+	parts := strings.Split(fields[0], "/")
+
+	for _, p := range parts {
+		if !specifierRe.MatchString(p) {
+			return specs.Platform{}, fmt.Errorf("%q is an invalid component of %q: platform specifier component must match %q", p, specifier, specifierRe.String())
+		}
+	}
+
+	var p specs.Platform
+	switch len(parts) {
+	case 1:
+		if isKnownOS(parts[0]) {
+			p.OS = normalizeOS(parts[0])
+			switch p.OS {
+			case "windows":
+				return specs.Platform{}, fmt.Errorf("invalid platform %q: OS windows requires an architecture", specifier)
+			}
+
+			p.Architecture, p.Variant = normalizeArch(runtime.GOARCH, "")
+			if p.Architecture == "arm" && p.Variant == "v7" {
+				p.Variant = ""
+			}
+		} else {
+			arch, variant := normalizeArch(parts[0], "")
+			if !isKnownArch(arch) {
+				return specs.Platform{}, fmt.Errorf("invalid platform %q: unknown operating system or architecture", specifier)
+			}
+
+			p.OS = normalizeOS("")
+			p.Architecture, p.Variant = arch, variant
+		}
+	case 2:
+		// in this case, we will assume that the first component is the
+		// architecture or the OS.
+		p.OS = normalizeOS(parts[0])
+		p.Architecture, p.Variant = normalizeArch(parts[1], "")
+
+		if isKnownOS(p.Architecture) {
+			// both components are actually a Go style os/architecture pair.
+			p.OS = normalizeOS(parts[1])
+			p.Architecture, p.Variant = normalizeArch(parts[0], "")
+			if !isKnownArch(p.Architecture) {
+				return specs.Platform{}, fmt.Errorf("invalid platform %q: unknown architecture %q", specifier, p.Architecture)
+			}
+		}
+
+		if !isKnownOS(p.OS) {
+			return specs.Platform{}, fmt.Errorf("invalid platform %q: unknown operating system %q", specifier, p.OS)
+		}
+		if !isKnownArch(p.Architecture) {
+			return specs.Platform{}, fmt.Errorf("invalid platform %q: unknown architecture %q", specifier, p.Architecture)
+		}
+	case 3:
+		// we have a fully specified variant, this is rare
+		p.OS = normalizeOS(parts[0])
+		if !isKnownOS(p.OS) {
+			return specs.Platform{}, fmt.Errorf("invalid platform %q: unknown operating system %q", specifier, p.OS)
+		}
+
+		p.Architecture, p.Variant = normalizeArch(parts[1], parts[2])
+		if !isKnownArch(p.Architecture) {
+			return specs.Platform{}, fmt.Errorf("invalid platform %q: unknown architecture %q", specifier, p.Architecture)
+		}
+	default:
+		return specs.Platform{}, fmt.Errorf("%q: cannot parse platform specifier: too many slashes", specifier)
+	}
+
+	for _, attr := range fields[1:] {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok {
+			return specs.Platform{}, fmt.Errorf("invalid platform specifier %q: malformed attribute %q", specifier, attr)
+		}
+
+		switch key {
+		case "osversion":
+			if value == "" {
+				return specs.Platform{}, fmt.Errorf("invalid platform specifier %q: empty osversion", specifier)
+			}
+			p.OSVersion = value
+		case "feature":
+			if value == "" {
+				return specs.Platform{}, fmt.Errorf("invalid platform specifier %q: empty feature list", specifier)
+			}
+			p.OSFeatures = strings.Split(value, ",")
+		default:
+			return specs.Platform{}, fmt.Errorf("invalid platform specifier %q: unknown attribute %q", specifier, key)
+		}
+	}
+
+	return p, nil
+}
+
+func normalizeOS(os string) string {
+	if os == "" {
+		return runtime.GOOS
+	}
+	return strings.ToLower(os)
+}
+
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "js": true,
+	"linux": true, "nacl": true, "netbsd": true, "openbsd": true,
+	"plan9": true, "solaris": true, "windows": true, "zos": true,
+}
+
+func isKnownOS(os string) bool {
+	return knownOS[strings.ToLower(os)]
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true,
+	"armbe": true, "arm64": true, "arm64be": true, "ppc64": true,
+	"ppc64le": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true,
+	"ppc": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+}
+
+func isKnownArch(arch string) bool {
+	return knownArch[strings.ToLower(arch)]
+}
+
+func normalizeArch(arch, variant string) (string, string) {
+	arch, variant = strings.ToLower(arch), strings.ToLower(variant)
+	switch arch {
+	case "i386":
+		arch = "386"
+		variant = ""
+	case "x86_64", "x86-64":
+		arch = "amd64"
+		variant = ""
+	case "aarch64", "arm64":
+		arch = "arm64"
+		switch variant {
+		case "8", "v8":
+			variant = ""
+		}
+	case "armhf":
+		arch = "arm"
+		variant = "v7"
+	case "armel":
+		arch = "arm"
+		variant = "v6"
+	case "arm":
+		switch variant {
+		case "", "7":
+			variant = "v7"
+		case "5", "6", "8":
+			variant = "v" + variant
+		}
+	}
+
+	return arch, variant
+}
+
+// Normalize validates and translates the platform to the canonical value.
+//
+// For example, the following are equivalent:
+//
+//	linux/i386
+//	linux/386
+//
+// The canonical form will be `linux/386`.
+func Normalize(platform specs.Platform) specs.Platform {
+	platform.OS = normalizeOS(platform.OS)
+	platform.Architecture, platform.Variant = normalizeArch(platform.Architecture, platform.Variant)
+
+	// these fields are opaque, so we don't normalize them further
+	return platform
+}
@@ -0,0 +1,237 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package platforms
+
+import (
+	"runtime"
+	"sort"
+	"testing"
+
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestMatchOSVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		requested specs.Platform
+		candidate specs.Platform
+		match     bool
+	}{
+		{
+			name:      "EmptyRequestedMatchesAny",
+			requested: specs.Platform{OS: "windows", Architecture: "amd64"},
+			candidate: specs.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+			match:     true,
+		},
+		{
+			name:      "ExactMatch",
+			requested: specs.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+			candidate: specs.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+			match:     true,
+		},
+		{
+			name:      "VersionMismatch",
+			requested: specs.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+			candidate: specs.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.19041.1"},
+			match:     false,
+		},
+		{
+			name:      "RequestedVersionButCandidateEmpty",
+			requested: specs.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+			candidate: specs.Platform{OS: "windows", Architecture: "amd64"},
+			match:     false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NewMatcher(tc.requested).Match(tc.candidate); got != tc.match {
+				t.Fatalf("Match() = %v, want %v", got, tc.match)
+			}
+		})
+	}
+}
+
+func TestMatchOSFeatures(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		requested specs.Platform
+		candidate specs.Platform
+		match     bool
+	}{
+		{
+			name:      "EmptyRequestedMatchesAny",
+			requested: specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+			candidate: specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon"}},
+			match:     true,
+		},
+		{
+			name:      "SubsetSatisfied",
+			requested: specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon"}},
+			candidate: specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon", "vfpv4"}},
+			match:     true,
+		},
+		{
+			name:      "MissingFeatureRejected",
+			requested: specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon", "sse4"}},
+			candidate: specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7", OSFeatures: []string{"neon"}},
+			match:     false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NewMatcher(tc.requested).Match(tc.candidate); got != tc.match {
+				t.Fatalf("Match() = %v, want %v", got, tc.match)
+			}
+		})
+	}
+}
+
+func TestMatchArmVariantFallback(t *testing.T) {
+	requested := specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	m := NewMatcher(requested)
+
+	for _, tc := range []struct {
+		variant string
+		match   bool
+	}{
+		{variant: "v7", match: true},
+		{variant: "v6", match: true},
+		{variant: "v5", match: true},
+		{variant: "v8", match: false},
+	} {
+		t.Run(tc.variant, func(t *testing.T) {
+			candidate := specs.Platform{OS: "linux", Architecture: "arm", Variant: tc.variant}
+			if got := m.Match(candidate); got != tc.match {
+				t.Fatalf("Match(%s) = %v, want %v", tc.variant, got, tc.match)
+			}
+		})
+	}
+}
+
+func TestLessOrdersArmVariantsByFallbackDistance(t *testing.T) {
+	requested := specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	m := NewMatcher(requested)
+
+	candidates := []specs.Platform{
+		{OS: "linux", Architecture: "arm", Variant: "v5"},
+		{OS: "linux", Architecture: "arm", Variant: "v7"},
+		{OS: "linux", Architecture: "arm", Variant: "v6"},
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return m.Less(candidates[i], candidates[j])
+	})
+
+	got := make([]string, len(candidates))
+	for i, c := range candidates {
+		got[i] = c.Variant
+	}
+
+	want := []string{"v7", "v6", "v5"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Less ordering = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLessRanksNonMatchLast(t *testing.T) {
+	requested := specs.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	m := NewMatcher(requested)
+
+	nonMatching := specs.Platform{OS: "linux", Architecture: "amd64"}
+	matching := specs.Platform{OS: "linux", Architecture: "arm", Variant: "v6"}
+
+	if !m.Less(matching, nonMatching) {
+		t.Fatalf("expected matching platform to rank ahead of a non-matching one")
+	}
+	if m.Less(nonMatching, matching) {
+		t.Fatalf("expected non-matching platform not to rank ahead of a matching one")
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	for _, p := range []specs.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm", Variant: "v7"},
+		{OS: "windows", Architecture: "amd64", OSVersion: "10.0.17763.1234"},
+		{OS: "linux", Architecture: "arm64", OSFeatures: []string{"sse4"}},
+	} {
+		formatted := Format(p)
+		parsed, err := Parse(formatted)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned unexpected error: %v", formatted, err)
+		}
+		if Format(parsed) != formatted {
+			t.Fatalf("round trip of %#v through %q produced %#v (%q)", p, formatted, parsed, Format(parsed))
+		}
+	}
+}
+
+func TestParseExtendedAttributes(t *testing.T) {
+	p, err := Parse("linux/arm/v7;feature=neon,sse4")
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+	if len(p.OSFeatures) != 2 || p.OSFeatures[0] != "neon" || p.OSFeatures[1] != "sse4" {
+		t.Fatalf("Parse OSFeatures = %v, want [neon sse4]", p.OSFeatures)
+	}
+
+	if _, err := Parse("linux/amd64;osversion="); err == nil {
+		t.Fatalf("Parse with empty osversion should have returned an error")
+	}
+
+	if _, err := Parse("linux/amd64;bogus=1"); err == nil {
+		t.Fatalf("Parse with unknown attribute should have returned an error")
+	}
+}
+
+func TestParseSingleComponent(t *testing.T) {
+	t.Run("OS", func(t *testing.T) {
+		p, err := Parse("linux")
+		if err != nil {
+			t.Fatalf("Parse returned unexpected error: %v", err)
+		}
+		if p.OS != "linux" || p.Architecture != runtime.GOARCH {
+			t.Fatalf("Parse(\"linux\") = %#v, want OS=linux, Architecture=%s", p, runtime.GOARCH)
+		}
+	})
+
+	t.Run("Arch", func(t *testing.T) {
+		p, err := Parse("arm64")
+		if err != nil {
+			t.Fatalf("Parse returned unexpected error: %v", err)
+		}
+		if p.OS != runtime.GOOS || p.Architecture != "arm64" {
+			t.Fatalf("Parse(\"arm64\") = %#v, want OS=%s, Architecture=arm64", p, runtime.GOOS)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := Parse("bogus"); err == nil {
+			t.Fatalf("Parse(\"bogus\") should have returned an error")
+		}
+	})
+}
+
+func TestParseUnknownOSOrArchInFullySpecified(t *testing.T) {
+	if _, err := Parse("bogus/amd64/v1"); err == nil {
+		t.Fatalf("Parse with unknown OS should have returned an error")
+	}
+
+	if _, err := Parse("linux/bogus/v1"); err == nil {
+		t.Fatalf("Parse with unknown architecture should have returned an error")
+	}
+}
@@ -0,0 +1,54 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package platforms
+
+import (
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	types "github.com/containerd/containerd/api/types"
+)
+
+// FromProto converts from a protobuf Platform type to the OCI runtime
+// platform type. Callers at the API boundary (image pull, content
+// filters, snapshotter Prepare, TaskService Create) should use this
+// rather than hand-rolling the field-by-field copy, so that new fields
+// only need to be threaded through in one place.
+func FromProto(platform *types.Platform) specs.Platform {
+	if platform == nil {
+		return specs.Platform{}
+	}
+
+	return specs.Platform{
+		OS:           platform.OS,
+		Architecture: platform.Architecture,
+		Variant:      platform.Variant,
+		OSVersion:    platform.OSVersion,
+		OSFeatures:   append([]string(nil), platform.OSFeatures...),
+	}
+}
+
+// ToProto converts from an OCI runtime platform type to the protobuf
+// Platform type used on the wire.
+func ToProto(platform specs.Platform) *types.Platform {
+	return &types.Platform{
+		OS:           platform.OS,
+		Architecture: platform.Architecture,
+		Variant:      platform.Variant,
+		OSVersion:    platform.OSVersion,
+		OSFeatures:   append([]string(nil), platform.OSFeatures...),
+	}
+}
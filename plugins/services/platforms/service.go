@@ -0,0 +1,84 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package platforms implements the Platforms gRPC service on top of
+// the containerd/platforms package, so that clients which cannot link
+// against Go (Rust, Python, Java BuildKit bindings, ...) get identical
+// match semantics to the daemon, including the CPU-variant fallback
+// rules for ARM.
+package platforms
+
+import (
+	"context"
+	"sort"
+
+	api "github.com/containerd/containerd/api/services/platforms/v1"
+	types "github.com/containerd/containerd/api/types"
+	platformsPkg "github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/plugins"
+	"github.com/containerd/plugin"
+	"github.com/containerd/plugin/registry"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	registry.Register(&plugin.Registration{
+		Type: plugins.GRPCPlugin,
+		ID:   "platforms",
+		InitFn: func(ic *plugin.InitContext) (interface{}, error) {
+			return NewService(), nil
+		},
+	})
+}
+
+// service implements api.PlatformsServer on top of the platforms
+// package's Matcher and DefaultSpec.
+type service struct {
+	api.UnimplementedPlatformsServer
+}
+
+// NewService returns a new PlatformsServer.
+func NewService() api.PlatformsServer {
+	return &service{}
+}
+
+// Register registers the service with the given gRPC server, following
+// the same pattern used by the other GRPCPlugin services.
+func (s *service) Register(server *grpc.Server) error {
+	api.RegisterPlatformsServer(server, s)
+	return nil
+}
+
+func (s *service) Match(ctx context.Context, req *api.PlatformMatchRequest) (*api.PlatformMatchResponse, error) {
+	matcher := platformsPkg.NewMatcher(platformsPkg.FromProto(req.Platform))
+
+	matched := make([]*types.Platform, 0, len(req.Candidates))
+	for _, candidate := range req.Candidates {
+		if matcher.Match(platformsPkg.FromProto(candidate)) {
+			matched = append(matched, candidate)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matcher.Less(platformsPkg.FromProto(matched[i]), platformsPkg.FromProto(matched[j]))
+	})
+
+	return &api.PlatformMatchResponse{Platforms: matched}, nil
+}
+
+func (s *service) Default(ctx context.Context, req *api.DefaultRequest) (*types.Platform, error) {
+	return platformsPkg.ToProto(platformsPkg.DefaultSpec()), nil
+}
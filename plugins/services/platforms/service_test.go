@@ -0,0 +1,66 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package platforms
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/containerd/containerd/api/services/platforms/v1"
+	types "github.com/containerd/containerd/api/types"
+	platformsPkg "github.com/containerd/containerd/platforms"
+)
+
+func TestServiceMatchOrdersByFallbackDistance(t *testing.T) {
+	s := NewService()
+
+	req := &api.PlatformMatchRequest{
+		Platform: &types.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+		Candidates: []*types.Platform{
+			{OS: "linux", Architecture: "arm", Variant: "v5"},
+			{OS: "linux", Architecture: "arm", Variant: "v7"},
+			{OS: "linux", Architecture: "amd64"},
+		},
+	}
+
+	resp, err := s.Match(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Match returned unexpected error: %v", err)
+	}
+
+	if len(resp.Platforms) != 2 {
+		t.Fatalf("Match returned %d platforms, want 2: %v", len(resp.Platforms), resp.Platforms)
+	}
+
+	if resp.Platforms[0].Variant != "v7" || resp.Platforms[1].Variant != "v5" {
+		t.Fatalf("Match ordering = [%s, %s], want [v7, v5]", resp.Platforms[0].Variant, resp.Platforms[1].Variant)
+	}
+}
+
+func TestServiceDefaultReturnsHostSpec(t *testing.T) {
+	s := NewService()
+
+	got, err := s.Default(context.Background(), &api.DefaultRequest{})
+	if err != nil {
+		t.Fatalf("Default returned unexpected error: %v", err)
+	}
+
+	want := platformsPkg.ToProto(platformsPkg.DefaultSpec())
+	if got.OS != want.OS || got.Architecture != want.Architecture || got.Variant != want.Variant {
+		t.Fatalf("Default() = %#v, want %#v", got, want)
+	}
+}